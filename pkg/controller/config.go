@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"fmt"
+	"regexp"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// defaultIgnoredNamespaces are skipped unless a Config explicitly overrides
+// IgnoredNamespaces (even with an empty, non-nil slice).
+var defaultIgnoredNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// Config controls which namespaces a NamespaceController watches and acts
+// on. It lets an operator run one instance per tenant, or exclude system
+// namespaces, without recompiling.
+type Config struct {
+	// WatchScope restricts reconciliation to these namespace names. An empty
+	// slice (the default) means every namespace is in scope, subject to the
+	// other fields below.
+	WatchScope []string
+
+	// IgnoredNamespaces are never reconciled unless labelled managedLabel,
+	// even if they match WatchScope, LabelSelector or NameRegex. Defaults to kube-system, kube-public and
+	// kube-node-lease when left nil; pass an empty, non-nil slice to manage
+	// every namespace including those.
+	IgnoredNamespaces []string
+
+	// LabelSelector is passed straight through to the namespace informer's
+	// ListOptions, so non-matching namespaces are never even received.
+	LabelSelector string
+
+	// NameRegex additionally restricts reconciliation to namespaces whose
+	// name matches, applied in-process since the API can't filter list
+	// requests by name pattern.
+	NameRegex string
+
+	// MetricsAddr is the address the /metrics, /healthz and /readyz
+	// endpoints are served on. Defaults to ":8080" when left empty.
+	MetricsAddr string
+}
+
+// withDefaults returns a copy of cfg with the zero-value fields that have a
+// sensible default filled in.
+func (cfg Config) withDefaults() Config {
+	if cfg.IgnoredNamespaces == nil {
+		cfg.IgnoredNamespaces = defaultIgnoredNamespaces
+	}
+	if cfg.MetricsAddr == "" {
+		cfg.MetricsAddr = ":8080"
+	}
+	return cfg
+}
+
+// inScope reports whether ns should be reconciled under cfg. managedLabel
+// always brings a namespace into scope, mirroring the override it already
+// has over the (now configurable) ignore list.
+func (cfg Config) inScope(ns *v1.Namespace) (bool, error) {
+	if isTrue(ns.Labels, managedLabel) {
+		return true, nil
+	}
+
+	for _, ignored := range cfg.IgnoredNamespaces {
+		if ns.Name == ignored {
+			return false, nil
+		}
+	}
+
+	if len(cfg.WatchScope) > 0 {
+		inScope := false
+		for _, name := range cfg.WatchScope {
+			if ns.Name == name {
+				inScope = true
+				break
+			}
+		}
+		if !inScope {
+			return false, nil
+		}
+	}
+
+	if cfg.NameRegex != "" {
+		matched, err := regexp.MatchString(cfg.NameRegex, ns.Name)
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// validate checks cfg.LabelSelector and cfg.NameRegex eagerly so a typo
+// surfaces at startup rather than as a silent empty watch or a recurring
+// "failed to evaluate watch scope" error on every reconcile.
+func (cfg Config) validate() error {
+	if cfg.LabelSelector != "" {
+		if _, err := labels.Parse(cfg.LabelSelector); err != nil {
+			return fmt.Errorf("invalid labelSelector %q: %s", cfg.LabelSelector, err.Error())
+		}
+	}
+	if cfg.NameRegex != "" {
+		if _, err := regexp.Compile(cfg.NameRegex); err != nil {
+			return fmt.Errorf("invalid nameRegex %q: %s", cfg.NameRegex, err.Error())
+		}
+	}
+	return nil
+}
@@ -3,51 +3,181 @@ package controller
 import (
 	"fmt"
 	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
-	"log"
-	"regexp"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+	"reflect"
 	"sync"
 	"time"
 )
 
+// defaultWorkers is the number of reconcile worker goroutines started by Run.
+const defaultWorkers = 2
+
 // NamespaceController watches the kubernetes api for changes to namespaces and
 // creates a RoleBinding for that particular namespace.
 type NamespaceController struct {
 	namespaceInformer cache.SharedIndexInformer
-	kclient           *kubernetes.Clientset
+	templateInformer  cache.SharedIndexInformer
+	kclient           kubernetes.Interface
+	cfg               Config
+
+	// queue holds namespace keys (their name) waiting to be reconciled by
+	// SyncHandler. Using a workqueue means informer callbacks never block on
+	// API calls, and failures are retried with exponential backoff instead
+	// of being dropped on the floor.
+	queue   workqueue.RateLimitingInterface
+	workers int
+
+	templatesMu sync.RWMutex
+	templates   map[string][]PolicyTemplate // keyed by "configmap-namespace/configmap-name"
+
+	resourcesMu      sync.Mutex
+	managedResources map[string]map[managedResourceKey]struct{} // keyed by namespace name
 }
 
-// Run starts the process for listening for namespace changes and acting upon those changes.
+// managedResourceKey identifies a LimitRange, ResourceQuota or NetworkPolicy
+// that the controller created, so it can be reconciled or cleaned up later.
+type managedResourceKey struct {
+	kind string
+	name string
+}
+
+// Run starts the informers, waits for their caches to sync, and then starts
+// c.workers reconcile goroutines that drain the workqueue until stopCh is
+// closed.
 func (c *NamespaceController) Run(stopCh <-chan struct{}, wg *sync.WaitGroup) {
+	defer utilruntime.HandleCrash()
+
 	// When this function completes, mark the go function as done
 	defer wg.Done()
+	defer c.queue.ShutDown()
 
 	// Increment wait group as we're about to execute a go function
 	wg.Add(1)
 
 	// Execute go function
 	go c.namespaceInformer.Run(stopCh)
+	go c.templateInformer.Run(stopCh)
+
+	if !cache.WaitForCacheSync(stopCh, c.namespaceInformer.HasSynced, c.templateInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("timed out waiting for informer caches to sync"))
+		return
+	}
+	informerLastSyncTimestamp.SetToCurrentTime()
+
+	for i := 0; i < c.workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	go c.serveMetrics(stopCh)
 
 	// Wait till we receive a stop signal
 	<-stopCh
 }
 
-// NewNamespaceController creates a new NewNamespaceController
-func NewNamespaceController(kclient *kubernetes.Clientset) *NamespaceController {
-	namespaceWatcher := &NamespaceController{}
+// runWorker processes items from the queue until it is shut down.
+func (c *NamespaceController) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem pops a single namespace key off the queue and runs
+// SyncHandler against it, requeuing with backoff on failure.
+func (c *NamespaceController) processNextWorkItem() bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.SyncHandler(key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("error syncing namespace %q, requeuing: %s", key, err.Error()))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// SyncHandler ensures the namespace identified by key has its managed
+// LimitRange/ResourceQuota/NetworkPolicy objects reconciled to match the
+// currently loaded PolicyTemplates. It is the unit of work the workqueue
+// drives, and is kept separate from event-handler plumbing so it can be
+// called directly in tests. Every call records its outcome and duration via
+// the namespaces_processed_total and reconcile_duration_seconds metrics.
+func (c *NamespaceController) SyncHandler(key string) error {
+	start := time.Now()
+	result := "failed"
+	defer func() {
+		namespacesProcessedTotal.WithLabelValues(result).Inc()
+		reconcileDurationSeconds.Observe(time.Since(start).Seconds())
+	}()
+
+	obj, exists, err := c.namespaceInformer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to fetch namespace %q from store: %s", key, err.Error())
+	}
+
+	if !exists {
+		c.resourcesMu.Lock()
+		delete(c.managedResources, key)
+		c.resourcesMu.Unlock()
+		result = "skipped"
+		return nil
+	}
+
+	result, err = c.reconcileNamespace(obj.(*v1.Namespace))
+	return err
+}
+
+// enqueueNamespace adds a namespace's key to the workqueue.
+func (c *NamespaceController) enqueueNamespace(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// NewNamespaceController creates a new NamespaceController scoped by cfg.
+// kclient is a kubernetes.Interface rather than the concrete *kubernetes.Clientset
+// so tests can substitute k8s.io/client-go/kubernetes/fake.
+func NewNamespaceController(kclient kubernetes.Interface, cfg Config) (*NamespaceController, error) {
+	cfg = cfg.withDefaults()
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid controller config: %s", err.Error())
+	}
+
+	namespaceWatcher := &NamespaceController{
+		cfg:              cfg,
+		queue:            workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		workers:          defaultWorkers,
+		templates:        make(map[string][]PolicyTemplate),
+		managedResources: make(map[string]map[managedResourceKey]struct{}),
+	}
 
 	// Create informer for watching Namespaces
 	namespaceInformer := cache.NewSharedIndexInformer(
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = cfg.LabelSelector
 				return kclient.CoreV1().Namespaces().List(options)
 			},
 			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = cfg.LabelSelector
 				return kclient.CoreV1().Namespaces().Watch(options)
 			},
 		},
@@ -57,89 +187,438 @@ func NewNamespaceController(kclient *kubernetes.Clientset) *NamespaceController
 	)
 
 	namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: namespaceWatcher.createCustomRules,
+		AddFunc: namespaceWatcher.enqueueNamespace,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			namespaceWatcher.enqueueNamespace(newObj)
+		},
+		DeleteFunc: namespaceWatcher.enqueueNamespace,
+	})
+
+	// Create informer for watching the ConfigMaps that hold PolicyTemplates.
+	// These are watched cluster-wide since a template can target namespaces
+	// other than the one it happens to be defined in.
+	templateInformer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return kclient.CoreV1().ConfigMaps(metav1.NamespaceAll).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return kclient.CoreV1().ConfigMaps(metav1.NamespaceAll).Watch(options)
+			},
+		},
+		&v1.ConfigMap{},
+		3*time.Minute,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+
+	templateInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: namespaceWatcher.handleTemplateChange,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			namespaceWatcher.handleTemplateChange(newObj)
+		},
+		DeleteFunc: namespaceWatcher.handleTemplateDelete,
 	})
 
 	namespaceWatcher.kclient = kclient
 	namespaceWatcher.namespaceInformer = namespaceInformer
+	namespaceWatcher.templateInformer = templateInformer
+
+	return namespaceWatcher, nil
+}
+
+// handleTemplateChange parses the PolicyTemplates carried by a labelled
+// ConfigMap, stores them, and re-applies them to every namespace currently
+// known to the namespace informer that matches the (possibly new) selector.
+func (c *NamespaceController) handleTemplateChange(obj interface{}) {
+	cm := obj.(*v1.ConfigMap)
+	if !isPolicyTemplateConfigMap(cm) {
+		return
+	}
+
+	templates, err := parsePolicyTemplates(cm)
+	if err != nil {
+		klog.Infof("Failed to load policy templates from %s/%s: %s", cm.Namespace, cm.Name, err.Error())
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", cm.Namespace, cm.Name)
+
+	c.templatesMu.Lock()
+	c.templates[key] = templates
+	c.templatesMu.Unlock()
+
+	klog.Infof("Loaded %d policy template(s) from %s", len(templates), key)
+
+	// Re-enqueue every namespace we already know about so SyncHandler
+	// re-renders against the new/updated templates.
+	for _, obj := range c.namespaceInformer.GetStore().List() {
+		c.enqueueNamespace(obj)
+	}
+}
+
+// handleTemplateDelete forgets a PolicyTemplate ConfigMap. It does not tear
+// down objects previously rendered from it; they are left in place until the
+// namespace is reconciled by another template or handler.
+func (c *NamespaceController) handleTemplateDelete(obj interface{}) {
+	cm, ok := obj.(*v1.ConfigMap)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		cm, ok = tombstone.Obj.(*v1.ConfigMap)
+		if !ok {
+			return
+		}
+	}
+
+	if !isPolicyTemplateConfigMap(cm) {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", cm.Namespace, cm.Name)
+
+	c.templatesMu.Lock()
+	delete(c.templates, key)
+	c.templatesMu.Unlock()
 
-	return namespaceWatcher
+	klog.Infof("Removed policy templates from %s", key)
 }
 
-func (c *NamespaceController) createCustomRules(obj interface{}) {
+// allTemplates returns every currently loaded PolicyTemplate, across all
+// PolicyTemplate ConfigMaps.
+func (c *NamespaceController) allTemplates() []PolicyTemplate {
+	c.templatesMu.RLock()
+	defer c.templatesMu.RUnlock()
 
-	namespaceObj := obj.(*v1.Namespace)
+	var all []PolicyTemplate
+	for _, templates := range c.templates {
+		all = append(all, templates...)
+	}
+	return all
+}
+
+// managedLabel opts a namespace into policy application even when
+// WatchScope, IgnoredNamespaces or NameRegex would leave it out of scope
+// (see Config); it does not override Config.LabelSelector, since namespaces
+// that selector filters out never reach the controller. exemptLabel opts a namespace out
+// of policy application even when it would otherwise be in scope. Both are
+// recognised with any value other than "false".
+const (
+	managedLabel = "namespaces.operator/managed"
+	exemptLabel  = "namespaces.operator/exempt"
+)
+
+func isTrue(labels map[string]string, key string) bool {
+	v, ok := labels[key]
+	return ok && v != "false"
+}
+
+// reconcileNamespace brings a namespace's LimitRange/ResourceQuota/
+// NetworkPolicy objects in line with the currently loaded PolicyTemplates.
+// SyncHandler calls this for every add/update of a Namespace so that label
+// changes (opting a namespace in or out via managedLabel/exemptLabel) and
+// drift in the created objects are both picked up without waiting for the
+// next namespace event. It returns the outcome ("created", "skipped" or
+// "failed") for the namespaces_processed_total metric alongside any error.
+func (c *NamespaceController) reconcileNamespace(namespaceObj *v1.Namespace) (string, error) {
 	namespaceName := namespaceObj.Name
 
-	adminNamespaces, _ := regexp.MatchString("kube-.*", namespaceName) // kube-system - kube-public
+	if isTrue(namespaceObj.Labels, exemptLabel) {
+		klog.Infof("Namespace %s is exempt, removing any managed policy objects", namespaceName)
+		if err := c.removeManagedResources(namespaceName); err != nil {
+			return "failed", err
+		}
+		return "skipped", nil
+	}
 
-	limitRangeName := fmt.Sprintf("lr-auto-%s", namespaceName)
+	inScope, err := c.cfg.inScope(namespaceObj)
+	if err != nil {
+		return "failed", fmt.Errorf("failed to evaluate watch scope for namespace %s: %s", namespaceName, err.Error())
+	}
+	if !inScope {
+		klog.Infof("Namespace %s is out of scope, removing any managed policy objects", namespaceName)
+		if err := c.removeManagedResources(namespaceName); err != nil {
+			return "failed", err
+		}
+		return "skipped", nil
+	}
 
-	if adminNamespaces != true {
+	matched := matchingTemplates(c.allTemplates(), namespaceObj)
+	if len(matched) == 0 {
+		// No PolicyTemplate matches this namespace: fall back to the
+		// operator's original baseline policy so clusters that haven't
+		// defined any PolicyTemplate yet see no change in behaviour.
+		matched = []PolicyTemplate{defaultPolicyTemplate}
+	}
 
-		// Add limitRange for memory
-		limit := &v1.LimitRange{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "LimitRange",
-				APIVersion: "v1",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      limitRangeName,
-				Namespace: namespaceName,
-			},
+	if err := c.applyTemplates(namespaceObj, matched); err != nil {
+		return "failed", err
+	}
+	return "created", nil
+}
 
-			Spec: v1.LimitRangeSpec{
-				Limits: []v1.LimitRangeItem{{
-					Type: v1.LimitTypeContainer,
-					Default: v1.ResourceList{
-						"memory": *resource.NewQuantity(128*1024*1024, resource.BinarySI),
-					},
-					DefaultRequest: v1.ResourceList{
-						"memory": *resource.NewQuantity(128*1024*1024, resource.BinarySI),
-					},
-				}},
-			},
+// removeManagedResources deletes every LimitRange/ResourceQuota/NetworkPolicy
+// the controller has created for namespaceName and clears its tracking entry.
+func (c *NamespaceController) removeManagedResources(namespaceName string) error {
+	c.resourcesMu.Lock()
+	keys := c.managedResources[namespaceName]
+	delete(c.managedResources, namespaceName)
+	c.resourcesMu.Unlock()
+
+	var errs []error
+	for key := range keys {
+		if err := c.deleteManagedResource(namespaceName, key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s %s/%s: %s", key.kind, namespaceName, key.name, err.Error()))
 		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
 
-		_, err := c.kclient.CoreV1().LimitRanges(namespaceName).Create(limit)
+// pruneManagedResources deletes any object the controller previously created
+// for namespaceName that is no longer in want, e.g. because a PolicyTemplate
+// selector changed or its ConfigMap was deleted so a different set of
+// templates now applies. Without this, a stale LimitRange/ResourceQuota/
+// NetworkPolicy named after a template that no longer matches would be left
+// in the namespace forever, alongside whatever applyTemplates just rendered.
+func (c *NamespaceController) pruneManagedResources(namespaceName string, want map[managedResourceKey]struct{}) error {
+	c.resourcesMu.Lock()
+	var stale []managedResourceKey
+	for key := range c.managedResources[namespaceName] {
+		if _, ok := want[key]; !ok {
+			stale = append(stale, key)
+		}
+	}
+	for _, key := range stale {
+		delete(c.managedResources[namespaceName], key)
+	}
+	c.resourcesMu.Unlock()
 
-		if err != nil {
-			log.Println(fmt.Sprintf("Failed to create limitRange: %s", err.Error()))
-		} else {
-			log.Println(fmt.Sprintf("limitRange for Namespace: %s created", namespaceName))
+	var errs []error
+	for _, key := range stale {
+		if err := c.deleteManagedResource(namespaceName, key); err != nil {
+			errs = append(errs, fmt.Errorf("failed to prune stale %s %s/%s: %s", key.kind, namespaceName, key.name, err.Error()))
+			continue
 		}
+		klog.Infof("%s %s for Namespace: %s removed, no longer desired", key.kind, key.name, namespaceName)
+	}
+	return utilerrors.NewAggregate(errs)
+}
 
+// deleteManagedResource deletes the single object identified by key from
+// namespaceName. A not-found error is swallowed since the end state (the
+// object is gone) is already what the caller wants.
+func (c *NamespaceController) deleteManagedResource(namespaceName string, key managedResourceKey) error {
+	var err error
+	switch key.kind {
+	case "LimitRange":
+		err = c.kclient.CoreV1().LimitRanges(namespaceName).Delete(key.name, &metav1.DeleteOptions{})
+	case "ResourceQuota":
+		err = c.kclient.CoreV1().ResourceQuotas(namespaceName).Delete(key.name, &metav1.DeleteOptions{})
+	case "NetworkPolicy":
+		err = c.kclient.NetworkingV1().NetworkPolicies(namespaceName).Delete(key.name, &metav1.DeleteOptions{})
+	}
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
 
-		// Add ResourceQuota for service.loadbalancer & service.nodeport
-		resourceQuotaName := fmt.Sprintf("rq-auto-%s", namespaceName)
+// trackManagedResource records that the controller manages the named object,
+// so it can be reconciled or cleaned up on exemption/deletion.
+func (c *NamespaceController) trackManagedResource(namespaceName, kind, name string) {
+	c.resourcesMu.Lock()
+	defer c.resourcesMu.Unlock()
 
-		quota := &v1.ResourceQuota{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "ResourceQuota",
-				APIVersion: "v1",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      resourceQuotaName,
-				Namespace: namespaceName,
-			},
-			Spec: v1.ResourceQuotaSpec{
-				Hard: v1.ResourceList{
-					"services.loadbalancers": *resource.NewQuantity(0,resource.BinarySI),
-					"services.nodeports": *resource.NewQuantity(0,resource.BinarySI),
-				},
-			},
+	if c.managedResources[namespaceName] == nil {
+		c.managedResources[namespaceName] = make(map[managedResourceKey]struct{})
+	}
+	c.managedResources[namespaceName][managedResourceKey{kind: kind, name: name}] = struct{}{}
+}
+
+// applyTemplates renders every LimitRange, ResourceQuota and NetworkPolicy
+// declared by templates into namespaceObj's namespace, creating them via the
+// API or updating them in place if they've drifted from the template's spec.
+// Each created object gets an OwnerReference back to the Namespace so it is
+// garbage-collected automatically if the namespace is deleted outside of our
+// own reconcile loop. Once every template has been rendered, anything
+// previously tracked for this namespace that isn't in the freshly rendered
+// set is pruned via pruneManagedResources, so a template that stops matching
+// (selector change, ConfigMap deletion) doesn't leave its objects behind.
+// Every failure is collected so one bad template doesn't stop the rest from
+// being applied; the aggregate is returned so the caller can requeue.
+func (c *NamespaceController) applyTemplates(namespaceObj *v1.Namespace, templates []PolicyTemplate) error {
+	namespaceName := namespaceObj.Name
+	ownerRef := *metav1.NewControllerRef(namespaceObj, v1.SchemeGroupVersion.WithKind("Namespace"))
+
+	var errs []error
+	want := make(map[managedResourceKey]struct{})
+
+	for _, tmpl := range templates {
+		for i, spec := range tmpl.LimitRanges {
+			name := limitRangeName(tmpl.Name, i, namespaceName)
+			want[managedResourceKey{kind: "LimitRange", name: name}] = struct{}{}
+
+			existing, err := c.kclient.CoreV1().LimitRanges(namespaceName).Get(name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				limit := &v1.LimitRange{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "LimitRange",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            name,
+						Namespace:       namespaceName,
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+					Spec: spec,
+				}
+
+				if _, err := c.kclient.CoreV1().LimitRanges(namespaceName).Create(limit); err != nil {
+					limitRangeApplyErrorsTotal.Inc()
+					errs = append(errs, fmt.Errorf("failed to create limitRange for template %s in %s: %s", tmpl.Name, namespaceName, err.Error()))
+					continue
+				}
+				klog.Infof("limitRange %s for Namespace: %s created", name, namespaceName)
+			} else if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get limitRange %s in %s: %s", name, namespaceName, err.Error()))
+				continue
+			} else if !reflect.DeepEqual(existing.Spec, spec) {
+				existing.Spec = spec
+				if _, err := c.kclient.CoreV1().LimitRanges(namespaceName).Update(existing); err != nil {
+					limitRangeApplyErrorsTotal.Inc()
+					errs = append(errs, fmt.Errorf("failed to reconcile drifted limitRange %s in %s: %s", name, namespaceName, err.Error()))
+					continue
+				}
+				klog.Infof("limitRange %s for Namespace: %s reconciled back to spec", name, namespaceName)
+			}
+
+			c.trackManagedResource(namespaceName, "LimitRange", name)
 		}
 
-		_, err = c.kclient.CoreV1().ResourceQuotas(namespaceName).Create(quota)
+		for i, spec := range tmpl.ResourceQuotas {
+			name := resourceQuotaName(tmpl.Name, i, namespaceName)
+			want[managedResourceKey{kind: "ResourceQuota", name: name}] = struct{}{}
+
+			existing, err := c.kclient.CoreV1().ResourceQuotas(namespaceName).Get(name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				quota := &v1.ResourceQuota{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "ResourceQuota",
+						APIVersion: "v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            name,
+						Namespace:       namespaceName,
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+					Spec: spec,
+				}
+
+				if _, err := c.kclient.CoreV1().ResourceQuotas(namespaceName).Create(quota); err != nil {
+					resourceQuotaApplyErrorsTotal.Inc()
+					errs = append(errs, fmt.Errorf("failed to create ResourceQuotas for template %s in %s: %s", tmpl.Name, namespaceName, err.Error()))
+					continue
+				}
+				klog.Infof("ResourceQuotas %s for Namespace: %s created", name, namespaceName)
+			} else if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get ResourceQuota %s in %s: %s", name, namespaceName, err.Error()))
+				continue
+			} else if !reflect.DeepEqual(existing.Spec, spec) {
+				existing.Spec = spec
+				if _, err := c.kclient.CoreV1().ResourceQuotas(namespaceName).Update(existing); err != nil {
+					resourceQuotaApplyErrorsTotal.Inc()
+					errs = append(errs, fmt.Errorf("failed to reconcile drifted ResourceQuota %s in %s: %s", name, namespaceName, err.Error()))
+					continue
+				}
+				klog.Infof("ResourceQuotas %s for Namespace: %s reconciled back to spec", name, namespaceName)
+			}
 
-		if err != nil {
-			log.Println(fmt.Sprintf("Failed to create ResourceQuotas: %s", err.Error()))
-		} else {
-			log.Println(fmt.Sprintf("ResourceQuotas for Namespace: %s created", namespaceName))
+			c.trackManagedResource(namespaceName, "ResourceQuota", name)
 		}
 
+		for i, spec := range tmpl.NetworkPolicies {
+			name := networkPolicyName(tmpl.Name, i, namespaceName)
+			want[managedResourceKey{kind: "NetworkPolicy", name: name}] = struct{}{}
+
+			existing, err := c.kclient.NetworkingV1().NetworkPolicies(namespaceName).Get(name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) {
+				netpol := &networkingv1.NetworkPolicy{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "NetworkPolicy",
+						APIVersion: "networking.k8s.io/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            name,
+						Namespace:       namespaceName,
+						OwnerReferences: []metav1.OwnerReference{ownerRef},
+					},
+					Spec: spec,
+				}
+
+				if _, err := c.kclient.NetworkingV1().NetworkPolicies(namespaceName).Create(netpol); err != nil {
+					networkPolicyApplyErrorsTotal.Inc()
+					errs = append(errs, fmt.Errorf("failed to create NetworkPolicy for template %s in %s: %s", tmpl.Name, namespaceName, err.Error()))
+					continue
+				}
+				klog.Infof("NetworkPolicy %s for Namespace: %s created", name, namespaceName)
+			} else if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get NetworkPolicy %s in %s: %s", name, namespaceName, err.Error()))
+				continue
+			} else if !reflect.DeepEqual(existing.Spec, spec) {
+				existing.Spec = spec
+				if _, err := c.kclient.NetworkingV1().NetworkPolicies(namespaceName).Update(existing); err != nil {
+					networkPolicyApplyErrorsTotal.Inc()
+					errs = append(errs, fmt.Errorf("failed to reconcile drifted NetworkPolicy %s in %s: %s", name, namespaceName, err.Error()))
+					continue
+				}
+				klog.Infof("NetworkPolicy %s for Namespace: %s reconciled back to spec", name, namespaceName)
+			}
+
+			c.trackManagedResource(namespaceName, "NetworkPolicy", name)
+		}
+	}
 
-	} else {
-		log.Println(fmt.Sprintf("Skip admin namespace: %s", namespaceName))
+	if err := c.pruneManagedResources(namespaceName, want); err != nil {
+		errs = append(errs, err)
 	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func limitRangeName(templateName string, index int, namespaceName string) string {
+	return fmt.Sprintf("lr-auto-%s-%d-%s", templateName, index, namespaceName)
+}
+
+func resourceQuotaName(templateName string, index int, namespaceName string) string {
+	return fmt.Sprintf("rq-auto-%s-%d-%s", templateName, index, namespaceName)
+}
+
+func networkPolicyName(templateName string, index int, namespaceName string) string {
+	return fmt.Sprintf("np-auto-%s-%d-%s", templateName, index, namespaceName)
+}
+
+// defaultPolicyTemplate reproduces the operator's original, hardcoded
+// behaviour: a 128Mi memory LimitRange and a zero LoadBalancer/NodePort
+// ResourceQuota. It applies whenever no PolicyTemplate ConfigMap matches a
+// namespace.
+var defaultPolicyTemplate = PolicyTemplate{
+	Name: "default",
+	LimitRanges: []v1.LimitRangeSpec{{
+		Limits: []v1.LimitRangeItem{{
+			Type: v1.LimitTypeContainer,
+			Default: v1.ResourceList{
+				"memory": *resource.NewQuantity(128*1024*1024, resource.BinarySI),
+			},
+			DefaultRequest: v1.ResourceList{
+				"memory": *resource.NewQuantity(128*1024*1024, resource.BinarySI),
+			},
+		}},
+	}},
+	ResourceQuotas: []v1.ResourceQuotaSpec{{
+		Hard: v1.ResourceList{
+			"services.loadbalancers": *resource.NewQuantity(0, resource.BinarySI),
+			"services.nodeports":     *resource.NewQuantity(0, resource.BinarySI),
+		},
+	}},
 }
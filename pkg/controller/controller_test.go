@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newTestController builds a NamespaceController backed by a fake clientset
+// and an unstarted namespace informer, so SyncHandler can be exercised
+// directly without standing up a real API server.
+func newTestController(cfg Config, kclient *fake.Clientset) *NamespaceController {
+	c := &NamespaceController{
+		cfg:              cfg.withDefaults(),
+		kclient:          kclient,
+		templates:        make(map[string][]PolicyTemplate),
+		managedResources: make(map[string]map[managedResourceKey]struct{}),
+	}
+	c.namespaceInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{},
+		&v1.Namespace{},
+		0,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	return c
+}
+
+func TestSyncHandlerCreatesDefaultPolicy(t *testing.T) {
+	kclient := fake.NewSimpleClientset()
+	c := newTestController(Config{}, kclient)
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	if err := c.namespaceInformer.GetStore().Add(ns); err != nil {
+		t.Fatalf("failed to seed namespace store: %s", err.Error())
+	}
+
+	if err := c.SyncHandler("team-a"); err != nil {
+		t.Fatalf("SyncHandler returned error: %s", err.Error())
+	}
+
+	name := limitRangeName(defaultPolicyTemplate.Name, 0, "team-a")
+	if _, err := kclient.CoreV1().LimitRanges("team-a").Get(name, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected LimitRange %s to be created: %s", name, err.Error())
+	}
+	if _, ok := c.managedResources["team-a"][managedResourceKey{kind: "LimitRange", name: name}]; !ok {
+		t.Errorf("expected LimitRange %s to be tracked as managed", name)
+	}
+}
+
+func TestSyncHandlerReconcilesDrift(t *testing.T) {
+	name := limitRangeName(defaultPolicyTemplate.Name, 0, "team-a")
+	drifted := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+		Spec:       v1.LimitRangeSpec{Limits: []v1.LimitRangeItem{{Type: v1.LimitTypePod}}},
+	}
+	kclient := fake.NewSimpleClientset(drifted)
+	c := newTestController(Config{}, kclient)
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	if err := c.namespaceInformer.GetStore().Add(ns); err != nil {
+		t.Fatalf("failed to seed namespace store: %s", err.Error())
+	}
+
+	if err := c.SyncHandler("team-a"); err != nil {
+		t.Fatalf("SyncHandler returned error: %s", err.Error())
+	}
+
+	got, err := kclient.CoreV1().LimitRanges("team-a").Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected LimitRange %s to still exist: %s", name, err.Error())
+	}
+	if got.Spec.Limits[0].Type != v1.LimitTypeContainer {
+		t.Errorf("expected drifted LimitRange to be reconciled back to the default template, got %+v", got.Spec)
+	}
+}
+
+func TestSyncHandlerExemptNamespaceRemovesManagedResources(t *testing.T) {
+	name := limitRangeName(defaultPolicyTemplate.Name, 0, "team-a")
+	existing := &v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"}}
+	kclient := fake.NewSimpleClientset(existing)
+	c := newTestController(Config{}, kclient)
+	c.managedResources["team-a"] = map[managedResourceKey]struct{}{
+		{kind: "LimitRange", name: name}: {},
+	}
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "team-a",
+		Labels: map[string]string{exemptLabel: "true"},
+	}}
+	if err := c.namespaceInformer.GetStore().Add(ns); err != nil {
+		t.Fatalf("failed to seed namespace store: %s", err.Error())
+	}
+
+	if err := c.SyncHandler("team-a"); err != nil {
+		t.Fatalf("SyncHandler returned error: %s", err.Error())
+	}
+
+	if _, err := kclient.CoreV1().LimitRanges("team-a").Get(name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected LimitRange %s to be removed for an exempt namespace, got err=%v", name, err)
+	}
+	if len(c.managedResources["team-a"]) != 0 {
+		t.Errorf("expected no managed resources tracked for an exempt namespace, got %v", c.managedResources["team-a"])
+	}
+}
+
+func TestSyncHandlerOutOfScopeNamespaceRemovesManagedResources(t *testing.T) {
+	name := limitRangeName(defaultPolicyTemplate.Name, 0, "team-a")
+	existing := &v1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"}}
+	kclient := fake.NewSimpleClientset(existing)
+	c := newTestController(Config{WatchScope: []string{"other"}}, kclient)
+	c.managedResources["team-a"] = map[managedResourceKey]struct{}{
+		{kind: "LimitRange", name: name}: {},
+	}
+
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	if err := c.namespaceInformer.GetStore().Add(ns); err != nil {
+		t.Fatalf("failed to seed namespace store: %s", err.Error())
+	}
+
+	if err := c.SyncHandler("team-a"); err != nil {
+		t.Fatalf("SyncHandler returned error: %s", err.Error())
+	}
+
+	if _, err := kclient.CoreV1().LimitRanges("team-a").Get(name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected LimitRange %s to be removed for an out-of-scope namespace, got err=%v", name, err)
+	}
+	if len(c.managedResources["team-a"]) != 0 {
+		t.Errorf("expected no managed resources tracked for an out-of-scope namespace, got %v", c.managedResources["team-a"])
+	}
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog"
+)
+
+var (
+	namespacesProcessedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "namespaces_processed_total",
+		Help: "Number of namespace reconciles, by outcome.",
+	}, []string{"result"})
+
+	limitRangeApplyErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "limitrange_apply_errors_total",
+		Help: "Number of LimitRange create/update failures.",
+	})
+
+	resourceQuotaApplyErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "resourcequota_apply_errors_total",
+		Help: "Number of ResourceQuota create/update failures.",
+	})
+
+	networkPolicyApplyErrorsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "networkpolicy_apply_errors_total",
+		Help: "Number of NetworkPolicy create/update failures.",
+	})
+
+	reconcileDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "reconcile_duration_seconds",
+		Help:    "Time spent in SyncHandler per namespace.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	informerLastSyncTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "informer_last_sync_timestamp",
+		Help: "Unix timestamp of the last successful informer cache sync.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		namespacesProcessedTotal,
+		limitRangeApplyErrorsTotal,
+		resourceQuotaApplyErrorsTotal,
+		networkPolicyApplyErrorsTotal,
+		reconcileDurationSeconds,
+		informerLastSyncTimestamp,
+	)
+}
+
+// serveMetrics exposes /metrics, /healthz and /readyz on c.cfg.MetricsAddr
+// until stopCh is closed. /readyz reflects whether both informer caches have
+// completed their initial sync.
+func (c *NamespaceController) serveMetrics(stopCh <-chan struct{}) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !c.namespaceInformer.HasSynced() || !c.templateInformer.HasSynced() {
+			http.Error(w, "informer caches not synced", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{Addr: c.cfg.MetricsAddr, Handler: mux}
+
+	go func() {
+		<-stopCh
+		server.Close()
+	}()
+
+	klog.Infof("Serving metrics and health checks on %s", c.cfg.MetricsAddr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		klog.Errorf("metrics server error: %s", err.Error())
+	}
+}
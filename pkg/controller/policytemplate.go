@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+)
+
+// PolicyTemplateLabel marks a ConfigMap as holding a PolicyTemplate definition.
+// Only ConfigMaps carrying this label (with any value) are parsed as templates.
+const PolicyTemplateLabel = "namespaces.operator/policy-template"
+
+// policyTemplateDataKey is the ConfigMap data key holding the JSON-encoded
+// list of PolicyTemplate objects.
+const policyTemplateDataKey = "templates"
+
+// PolicyTemplate declares a set of LimitRange, ResourceQuota and
+// NetworkPolicy specs that should be applied to every namespace matched by
+// Selector. Templates are loaded from ConfigMaps labelled with
+// PolicyTemplateLabel so that policy can be changed without recompiling the
+// operator.
+type PolicyTemplate struct {
+	Name            string                           `json:"name"`
+	Selector        PolicyTemplateSelector           `json:"selector"`
+	LimitRanges     []v1.LimitRangeSpec              `json:"limitRanges,omitempty"`
+	ResourceQuotas  []v1.ResourceQuotaSpec           `json:"resourceQuotas,omitempty"`
+	NetworkPolicies []networkingv1.NetworkPolicySpec `json:"networkPolicies,omitempty"`
+}
+
+// PolicyTemplateSelector chooses which namespaces a PolicyTemplate applies to.
+// A namespace must satisfy both fields that are set; a selector with neither
+// field set matches nothing.
+type PolicyTemplateSelector struct {
+	LabelSelector string `json:"labelSelector,omitempty"`
+	NameRegex     string `json:"nameRegex,omitempty"`
+}
+
+// validate checks s.LabelSelector and s.NameRegex eagerly so a typo in a
+// PolicyTemplate surfaces once, when its ConfigMap is loaded, instead of as a
+// recurring "Skipping policy template" log on every namespace reconcile.
+func (s PolicyTemplateSelector) validate() error {
+	if s.LabelSelector != "" {
+		if _, err := labels.Parse(s.LabelSelector); err != nil {
+			return fmt.Errorf("invalid labelSelector %q: %s", s.LabelSelector, err.Error())
+		}
+	}
+	if s.NameRegex != "" {
+		if _, err := regexp.Compile(s.NameRegex); err != nil {
+			return fmt.Errorf("invalid nameRegex %q: %s", s.NameRegex, err.Error())
+		}
+	}
+	return nil
+}
+
+// Matches reports whether ns satisfies the selector.
+func (s PolicyTemplateSelector) Matches(ns *v1.Namespace) (bool, error) {
+	if s.LabelSelector == "" && s.NameRegex == "" {
+		return false, nil
+	}
+
+	if s.LabelSelector != "" {
+		selector, err := labels.Parse(s.LabelSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid labelSelector %q: %s", s.LabelSelector, err.Error())
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			return false, nil
+		}
+	}
+
+	if s.NameRegex != "" {
+		matched, err := regexp.MatchString(s.NameRegex, ns.Name)
+		if err != nil {
+			return false, fmt.Errorf("invalid nameRegex %q: %s", s.NameRegex, err.Error())
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// isPolicyTemplateConfigMap reports whether cm carries the PolicyTemplateLabel.
+func isPolicyTemplateConfigMap(cm *v1.ConfigMap) bool {
+	_, ok := cm.Labels[PolicyTemplateLabel]
+	return ok
+}
+
+// parsePolicyTemplates decodes the PolicyTemplate list stored under
+// policyTemplateDataKey in cm.Data.
+func parsePolicyTemplates(cm *v1.ConfigMap) ([]PolicyTemplate, error) {
+	raw, ok := cm.Data[policyTemplateDataKey]
+	if !ok {
+		return nil, fmt.Errorf("configmap %s/%s is missing the %q key", cm.Namespace, cm.Name, policyTemplateDataKey)
+	}
+
+	var templates []PolicyTemplate
+	if err := json.Unmarshal([]byte(raw), &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse policy templates in %s/%s: %s", cm.Namespace, cm.Name, err.Error())
+	}
+
+	for _, tmpl := range templates {
+		if err := tmpl.Selector.validate(); err != nil {
+			return nil, fmt.Errorf("policy template %q in %s/%s has an invalid selector: %s", tmpl.Name, cm.Namespace, cm.Name, err.Error())
+		}
+	}
+
+	return templates, nil
+}
+
+// matchingTemplates returns the subset of templates whose selector matches ns.
+func matchingTemplates(templates []PolicyTemplate, ns *v1.Namespace) []PolicyTemplate {
+	var matched []PolicyTemplate
+	for _, tmpl := range templates {
+		ok, err := tmpl.Selector.Matches(ns)
+		if err != nil {
+			klog.Infof("Skipping policy template %s for namespace %s: %s", tmpl.Name, ns.Name, err.Error())
+			continue
+		}
+		if ok {
+			matched = append(matched, tmpl)
+		}
+	}
+	return matched
+}